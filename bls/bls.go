@@ -0,0 +1,115 @@
+// Package bls wraps the BLS12-381 primitives needed to aggregate IBFT
+// Commit signatures into a single quorum certificate.
+package bls
+
+import (
+	"errors"
+
+	bls12381 "github.com/kilic/bls12-381"
+)
+
+var errSignatureMismatch = errors.New("aggregated signature does not verify against the aggregated public key")
+
+// PublicKey is a BLS12-381 public key
+type PublicKey struct {
+	point *bls12381.PointG1
+}
+
+// Signature is a BLS12-381 signature
+type Signature struct {
+	point *bls12381.PointG2
+}
+
+// UnmarshalPublicKey parses a compressed BLS12-381 public key
+func UnmarshalPublicKey(raw []byte) (*PublicKey, error) {
+	point, err := bls12381.NewG1().FromCompressed(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PublicKey{point: point}, nil
+}
+
+// Marshal returns the compressed byte representation of the public key
+func (k *PublicKey) Marshal() []byte {
+	return bls12381.NewG1().ToCompressed(k.point)
+}
+
+// UnmarshalSignature parses a compressed BLS12-381 signature
+func UnmarshalSignature(raw []byte) (*Signature, error) {
+	point, err := bls12381.NewG2().FromCompressed(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Signature{point: point}, nil
+}
+
+// Marshal returns the compressed byte representation of the signature
+func (s *Signature) Marshal() []byte {
+	return bls12381.NewG2().ToCompressed(s.point)
+}
+
+// AggregateSignatures combines signatures into a single aggregated signature
+func AggregateSignatures(signatures []*Signature) (*Signature, error) {
+	if len(signatures) == 0 {
+		return nil, errors.New("no signatures to aggregate")
+	}
+
+	g2 := bls12381.NewG2()
+	aggregated := g2.Zero()
+
+	for _, signature := range signatures {
+		g2.Add(aggregated, aggregated, signature.point)
+	}
+
+	return &Signature{point: aggregated}, nil
+}
+
+// AggregatePublicKeys combines public keys into a single aggregated key,
+// used to verify an aggregated signature against a subset of signers
+func AggregatePublicKeys(keys []*PublicKey) (*PublicKey, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("no public keys to aggregate")
+	}
+
+	g1 := bls12381.NewG1()
+	aggregated := g1.Zero()
+
+	for _, key := range keys {
+		g1.Add(aggregated, aggregated, key.point)
+	}
+
+	return &PublicKey{point: aggregated}, nil
+}
+
+// Verify checks signature against msgHash for the aggregated public key k
+func (k *PublicKey) Verify(signature *Signature, msgHash []byte) bool {
+	engine := bls12381.NewEngine()
+
+	g2 := bls12381.NewG2()
+	hashPoint, err := g2.HashToCurve(msgHash, nil)
+	if err != nil {
+		return false
+	}
+
+	engine.AddPair(bls12381.NewG1().One(), signature.point)
+	engine.AddPairInv(k.point, hashPoint)
+
+	return engine.Check()
+}
+
+// VerifyAggregated is a convenience wrapper erroring instead of returning
+// a bare bool, for callers that want an explanatory error
+func VerifyAggregated(keys []*PublicKey, signature *Signature, msgHash []byte) error {
+	aggregatedKey, err := AggregatePublicKeys(keys)
+	if err != nil {
+		return err
+	}
+
+	if !aggregatedKey.Verify(signature, msgHash) {
+		return errSignatureMismatch
+	}
+
+	return nil
+}
@@ -0,0 +1,141 @@
+package core
+
+import (
+	"bytes"
+	"math/big"
+	"sort"
+
+	"github.com/tarality/0xTaral/bls"
+	"github.com/tarality/0xTaral/messages/proto"
+)
+
+// BLSPublicKeyProvider is an optional extension of ValidatorBackend for
+// backends that can supply each validator's BLS public key, enabling
+// aggregated Commit quorum certificates instead of one signature per signer.
+type BLSPublicKeyProvider interface {
+	// PublicKey returns the BLS public key registered for addr
+	PublicKey(addr string) (*bls.PublicKey, error)
+}
+
+// VerifyAggregatedQuorum checks that qc's signer bitmap carries enough
+// voting power to reach quorum, and that the aggregated signature
+// verifies against the aggregate of the selected signers' BLS public keys
+func (vm *ValidatorManager) VerifyAggregatedQuorum(qc *proto.AggregatedQuorumCertificate, msgHash []byte) bool {
+	provider, ok := vm.backend.(BLSPublicKeyProvider)
+	if !ok {
+		return false
+	}
+
+	vm.vpLock.RLock()
+	defer vm.vpLock.RUnlock()
+
+	addrs := sortedValidatorAddresses(vm.validatorsVotingPower)
+
+	signerPower := big.NewInt(0)
+	publicKeys := make([]*bls.PublicKey, 0, len(addrs))
+
+	for i, addr := range addrs {
+		if !bitmapIsSet(qc.Signers, i) {
+			continue
+		}
+
+		publicKey, err := provider.PublicKey(addr)
+		if err != nil {
+			vm.log.Error("VerifyAggregatedQuorum - failed to fetch BLS public key")
+
+			return false
+		}
+
+		publicKeys = append(publicKeys, publicKey)
+		signerPower.Add(signerPower, vm.validatorsVotingPower[addr])
+	}
+
+	if signerPower.Cmp(vm.quorumSize) < 0 {
+		return false
+	}
+
+	signature, err := bls.UnmarshalSignature(qc.AggregatedSignature)
+	if err != nil {
+		return false
+	}
+
+	return bls.VerifyAggregated(publicKeys, signature, qc.MessageHash) == nil && bytes.Equal(qc.MessageHash, msgHash)
+}
+
+// BuildAggregatedQuorumCertificate aggregates the BLS Commit signatures in
+// signatures (keyed by validator address) into a single
+// AggregatedQuorumCertificate, provided the backend implements
+// BLSPublicKeyProvider and every currently known validator has a signature
+// to contribute. Callers (the IBFT state machine's Commit step) should fall
+// back to sending msgs as individual per-signer Commit messages whenever ok
+// is false, e.g. because a validator hasn't advertised a BLS key or hasn't
+// signed yet.
+func (vm *ValidatorManager) BuildAggregatedQuorumCertificate(
+	signatures map[string]*bls.Signature,
+	msgHash []byte,
+) (qc *proto.AggregatedQuorumCertificate, ok bool, err error) {
+	if _, isProvider := vm.backend.(BLSPublicKeyProvider); !isProvider {
+		return nil, false, nil
+	}
+
+	vm.vpLock.RLock()
+	defer vm.vpLock.RUnlock()
+
+	addrs := sortedValidatorAddresses(vm.validatorsVotingPower)
+
+	sigs := make([]*bls.Signature, 0, len(addrs))
+	bitmap := make([]byte, (len(addrs)+7)/8)
+	signerPower := big.NewInt(0)
+
+	for i, addr := range addrs {
+		signature, signed := signatures[addr]
+		if !signed {
+			// Not every validator has signed (or advertised a BLS key) yet,
+			// so an aggregated certificate can't be built for this round.
+			return nil, false, nil
+		}
+
+		sigs = append(sigs, signature)
+		bitmap[i/8] |= 1 << uint(i%8)
+		signerPower.Add(signerPower, vm.validatorsVotingPower[addr])
+	}
+
+	if signerPower.Cmp(vm.quorumSize) < 0 {
+		return nil, false, nil
+	}
+
+	aggregated, err := bls.AggregateSignatures(sigs)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return &proto.AggregatedQuorumCertificate{
+		Signers:             bitmap,
+		AggregatedSignature: aggregated.Marshal(),
+		MessageHash:         msgHash,
+	}, true, nil
+}
+
+// sortedValidatorAddresses returns the validator addresses in a stable,
+// deterministic order so a bitmap index always refers to the same validator
+func sortedValidatorAddresses(votingPower map[string]*big.Int) []string {
+	addrs := make([]string, 0, len(votingPower))
+	for addr := range votingPower {
+		addrs = append(addrs, addr)
+	}
+
+	sort.Strings(addrs)
+
+	return addrs
+}
+
+// bitmapIsSet reports whether bit index is set in bitmap
+func bitmapIsSet(bitmap []byte, index int) bool {
+	byteIndex := index / 8
+	if byteIndex >= len(bitmap) {
+		return false
+	}
+
+	return bitmap[byteIndex]&(1<<uint(index%8)) != 0
+}
+
@@ -0,0 +1,99 @@
+package core
+
+import (
+	"errors"
+	"math"
+	"math/big"
+)
+
+// errQuorumNotBFTSafe is returned when a QuorumPolicy that requires BFT
+// safety produces a quorum size below the classic floor(2*totalVotingPower/3)+1
+// bound, and therefore cannot tolerate floor((n-1)/3) Byzantine faults.
+var errQuorumNotBFTSafe = errors.New("quorum policy does not tolerate floor((n-1)/3) byzantine faults")
+
+// QuorumPolicy determines how much aggregated voting power is required
+// for the validator set to reach quorum
+type QuorumPolicy interface {
+	// QuorumSize returns the voting power required to reach quorum
+	// for the given total voting power
+	QuorumSize(totalVotingPower *big.Int) *big.Int
+
+	// RequiresBFT reports whether this policy must tolerate the classic
+	// floor((n-1)/3) Byzantine fault bound
+	RequiresBFT() bool
+}
+
+// ByzantineFaultTolerantQuorum is the classic BFT quorum:
+// FLOOR(2 * totalVotingPower / 3) + 1, tolerating up to floor((n-1)/3)
+// faulty voting power
+type ByzantineFaultTolerantQuorum struct{}
+
+// QuorumSize returns FLOOR(2 * totalVotingPower / 3) + 1
+func (ByzantineFaultTolerantQuorum) QuorumSize(totalVotingPower *big.Int) *big.Int {
+	quorum := new(big.Int).Mul(totalVotingPower, big.NewInt(2))
+
+	return quorum.Div(quorum, big.NewInt(3)).Add(quorum, big.NewInt(1))
+}
+
+// RequiresBFT always returns true for the classic BFT quorum
+func (ByzantineFaultTolerantQuorum) RequiresBFT() bool {
+	return true
+}
+
+// PercentageQuorum requires CEIL(totalVotingPower * Numerator / Denominator)
+// voting power to reach quorum, e.g. {6, 10} reproduces the legacy 60% threshold
+type PercentageQuorum struct {
+	Numerator   int64
+	Denominator int64
+}
+
+// QuorumSize returns CEIL(totalVotingPower * Numerator / Denominator)
+func (p PercentageQuorum) QuorumSize(totalVotingPower *big.Int) *big.Int {
+	totalVotingPowerFloat := new(big.Float).SetInt(totalVotingPower)
+	percentageFloat := new(big.Float).Quo(big.NewFloat(float64(p.Numerator)), big.NewFloat(float64(p.Denominator)))
+
+	quorumFloat := new(big.Float).Mul(percentageFloat, totalVotingPowerFloat)
+
+	quorumFloat64, _ := quorumFloat.Float64()
+	quorumCeilBigFloat := new(big.Float).SetFloat64(math.Ceil(quorumFloat64))
+
+	quorumBigInt := new(big.Int)
+	quorumCeilBigFloat.Int(quorumBigInt)
+
+	return quorumBigInt
+}
+
+// RequiresBFT returns false since an arbitrary percentage is not
+// guaranteed to tolerate floor((n-1)/3) Byzantine faults
+func (p PercentageQuorum) RequiresBFT() bool {
+	return false
+}
+
+// CustomQuorum lets operators supply their own quorum calculation.
+// Set BFTSafe to true only if Func is known to satisfy the classic
+// floor(2*totalVotingPower/3)+1 bound
+type CustomQuorum struct {
+	Func    func(totalVotingPower *big.Int) *big.Int
+	BFTSafe bool
+}
+
+// QuorumSize delegates to Func
+func (c CustomQuorum) QuorumSize(totalVotingPower *big.Int) *big.Int {
+	return c.Func(totalVotingPower)
+}
+
+// RequiresBFT returns BFTSafe
+func (c CustomQuorum) RequiresBFT() bool {
+	return c.BFTSafe
+}
+
+// validateBFTSafety makes sure quorumSize tolerates up to floor((n-1)/3)
+// faulty voting power out of totalVotingPower, the classic BFT bound
+func validateBFTSafety(quorumSize, totalVotingPower *big.Int) error {
+	bftQuorum := ByzantineFaultTolerantQuorum{}.QuorumSize(totalVotingPower)
+	if quorumSize.Cmp(bftQuorum) < 0 {
+		return errQuorumNotBFTSafe
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package core
+
+import "math/big"
+
+// VoteOption is a governance-style vote choice, borrowed from the
+// Cosmos SDK gov tally model
+type VoteOption int
+
+const (
+	VoteYes VoteOption = iota
+	VoteNo
+	VoteAbstain
+	VoteNoWithVeto
+)
+
+// TallyThresholds are the fractions (in [0, 1]) a Tally must clear to pass
+type TallyThresholds struct {
+	// Quorum is the minimum turnout (participating power / total power) required
+	Quorum *big.Float
+
+	// Threshold is the minimum yes-fraction, excluding abstain from the
+	// denominator, required to pass
+	Threshold *big.Float
+
+	// Veto is the fraction of total power, including abstain, that
+	// vetoes the vote regardless of Threshold
+	Veto *big.Float
+}
+
+// DefaultTallyThresholds mirrors the Cosmos SDK gov module's defaults:
+// 33.4% quorum, 50% threshold, 33.4% veto
+func DefaultTallyThresholds() TallyThresholds {
+	return TallyThresholds{
+		Quorum:    big.NewFloat(0.334),
+		Threshold: big.NewFloat(0.5),
+		Veto:      big.NewFloat(0.334),
+	}
+}
+
+// TallyResult reports the outcome of a weighted vote
+type TallyResult struct {
+	YesPower     *big.Int
+	NoPower      *big.Int
+	AbstainPower *big.Int
+	VetoPower    *big.Int
+	TotalPower   *big.Int
+
+	// Turnout is participating power (yes+no+abstain+veto) over TotalPower
+	Turnout *big.Float
+
+	// YesFraction is YesPower over (yes+no+veto), excluding abstain
+	YesFraction *big.Float
+
+	// VetoFraction is VetoPower over TotalPower
+	VetoFraction *big.Float
+
+	// Passed reports whether Turnout, YesFraction and VetoFraction clear
+	// the configured TallyThresholds
+	Passed bool
+}
+
+// Tally aggregates votes, keyed by validator address, into a TallyResult
+// weighted by each validator's current voting power. Addresses outside
+// the current validator set are ignored
+func (vm *ValidatorManager) Tally(votes map[string]VoteOption) TallyResult {
+	vm.vpLock.RLock()
+	defer vm.vpLock.RUnlock()
+
+	yes := big.NewInt(0)
+	no := big.NewInt(0)
+	abstain := big.NewInt(0)
+	veto := big.NewInt(0)
+
+	for addr, option := range votes {
+		power, ok := vm.validatorsVotingPower[addr]
+		if !ok {
+			continue
+		}
+
+		switch option {
+		case VoteYes:
+			yes.Add(yes, power)
+		case VoteNo:
+			no.Add(no, power)
+		case VoteAbstain:
+			abstain.Add(abstain, power)
+		case VoteNoWithVeto:
+			veto.Add(veto, power)
+		}
+	}
+
+	participating := new(big.Int).Add(yes, no)
+	participating.Add(participating, abstain)
+	participating.Add(participating, veto)
+
+	yesDenominator := new(big.Int).Add(yes, no)
+	yesDenominator.Add(yesDenominator, veto)
+
+	result := TallyResult{
+		YesPower:     yes,
+		NoPower:      no,
+		AbstainPower: abstain,
+		VetoPower:    veto,
+		TotalPower:   vm.totalVotingPower,
+		Turnout:      votingFraction(participating, vm.totalVotingPower),
+		YesFraction:  votingFraction(yes, yesDenominator),
+		VetoFraction: votingFraction(veto, vm.totalVotingPower),
+	}
+
+	result.Passed = result.Turnout.Cmp(vm.tallyThresholds.Quorum) >= 0 &&
+		result.VetoFraction.Cmp(vm.tallyThresholds.Veto) < 0 &&
+		result.YesFraction.Cmp(vm.tallyThresholds.Threshold) >= 0
+
+	return result
+}
+
+// votingFraction returns numerator/denominator as a big.Float, or 0 if
+// denominator is zero
+func votingFraction(numerator, denominator *big.Int) *big.Float {
+	if denominator.Sign() == 0 {
+		return big.NewFloat(0)
+	}
+
+	return new(big.Float).Quo(new(big.Float).SetInt(numerator), new(big.Float).SetInt(denominator))
+}
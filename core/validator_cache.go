@@ -0,0 +1,87 @@
+package core
+
+import (
+	"container/list"
+	"sync"
+)
+
+// defaultSnapshotCacheSize is the number of per-height validator
+// snapshots kept in memory before the least recently used entry is evicted
+const defaultSnapshotCacheSize = 16
+
+// snapshotCacheEntry pairs a height with its cached snapshot so the
+// backing element can be removed from the lookup map on eviction
+type snapshotCacheEntry struct {
+	height   uint64
+	snapshot *validatorSnapshot
+}
+
+// snapshotCache is a fixed-size LRU cache of validatorSnapshot keyed by height
+type snapshotCache struct {
+	mux     sync.Mutex
+	size    int
+	entries map[uint64]*list.Element
+	order   *list.List
+}
+
+// newSnapshotCache creates a snapshotCache holding up to size entries.
+// A non-positive size falls back to defaultSnapshotCacheSize
+func newSnapshotCache(size int) *snapshotCache {
+	if size <= 0 {
+		size = defaultSnapshotCacheSize
+	}
+
+	return &snapshotCache{
+		size:    size,
+		entries: make(map[uint64]*list.Element),
+		order:   list.New(),
+	}
+}
+
+// get returns the cached snapshot for height, if present, and marks it
+// as the most recently used entry
+func (c *snapshotCache) get(height uint64) (*validatorSnapshot, bool) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	element, ok := c.entries[height]
+	if !ok {
+		return nil, false
+	}
+
+	c.order.MoveToFront(element)
+
+	return element.Value.(*snapshotCacheEntry).snapshot, true
+}
+
+// put stores snapshot under height, evicting the least recently used
+// entry if the cache is full
+func (c *snapshotCache) put(height uint64, snapshot *validatorSnapshot) {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if element, ok := c.entries[height]; ok {
+		element.Value.(*snapshotCacheEntry).snapshot = snapshot
+		c.order.MoveToFront(element)
+
+		return
+	}
+
+	element := c.order.PushFront(&snapshotCacheEntry{height: height, snapshot: snapshot})
+	c.entries[height] = element
+
+	if c.order.Len() > c.size {
+		c.evictOldest()
+	}
+}
+
+// evictOldest removes the least recently used entry from the cache
+func (c *snapshotCache) evictOldest() {
+	oldest := c.order.Back()
+	if oldest == nil {
+		return
+	}
+
+	c.order.Remove(oldest)
+	delete(c.entries, oldest.Value.(*snapshotCacheEntry).height)
+}
@@ -3,7 +3,6 @@ package core
 import (
 	"bytes"
 	"errors"
-	"math"
 	"math/big"
 	"sync"
 
@@ -20,6 +19,23 @@ type ValidatorBackend interface {
 	GetVotingPowers(height uint64) (map[string]*big.Int, error)
 }
 
+// ValidatorSetHasher is an optional extension of ValidatorBackend.
+// Backends that implement it let ValidatorManager detect validator set
+// changes at a given height without paying for a full GetVotingPowers call.
+type ValidatorSetHasher interface {
+	// ValidatorSetHash returns a hash identifying the validator set at height
+	ValidatorSetHash(height uint64) ([]byte, error)
+}
+
+// validatorSnapshot is a cached voting-power view of the validator set
+// at a specific height
+type validatorSnapshot struct {
+	setHash               []byte
+	totalVotingPower      *big.Int
+	quorumSize            *big.Int
+	validatorsVotingPower map[string]*big.Int
+}
+
 // ValidatorManager keeps voting power and other information about validators
 type ValidatorManager struct {
 	vpLock *sync.RWMutex
@@ -27,73 +43,228 @@ type ValidatorManager struct {
 	// quorumSize represents quorum for the height specified in the current View
 	quorumSize *big.Int
 
+	// totalVotingPower is the sum of all validatorsVotingPower for
+	// the height specified in the current View
+	totalVotingPower *big.Int
+
 	// validatorsVotingPower is a map of the validator addresses on their voting power for
 	// the height specified in the current View
 	validatorsVotingPower map[string]*big.Int
 
+	// policy decides how quorumSize is derived from totalVotingPower
+	policy QuorumPolicy
+
+	// tallyThresholds configures the fractions Tally checks Passed against
+	tallyThresholds TallyThresholds
+
+	// snapshots caches validatorSnapshot by height so repeated Init calls
+	// for the same height, and historical lookups, don't hit the backend
+	snapshots *snapshotCache
+
 	backend ValidatorBackend
 
 	log Logger
 }
 
-// NewValidatorManager creates new ValidatorManager
-func NewValidatorManager(backend ValidatorBackend, log Logger) *ValidatorManager {
+// NewValidatorManager creates new ValidatorManager.
+// If policy is nil, ByzantineFaultTolerantQuorum is used, restoring the
+// classic BFT-safe default. Pass PercentageQuorum{6, 10} to opt into the
+// legacy 60% threshold behavior instead.
+// A zero-value tallyThresholds falls back to DefaultTallyThresholds().
+func NewValidatorManager(
+	backend ValidatorBackend,
+	log Logger,
+	policy QuorumPolicy,
+	tallyThresholds TallyThresholds,
+) *ValidatorManager {
+	if policy == nil {
+		policy = ByzantineFaultTolerantQuorum{}
+	}
+
+	if tallyThresholds.Quorum == nil || tallyThresholds.Threshold == nil || tallyThresholds.Veto == nil {
+		tallyThresholds = DefaultTallyThresholds()
+	}
+
 	return &ValidatorManager{
 		quorumSize:            big.NewInt(0),
+		totalVotingPower:      big.NewInt(0),
 		backend:               backend,
 		validatorsVotingPower: nil,
+		policy:                policy,
+		tallyThresholds:       tallyThresholds,
+		snapshots:             newSnapshotCache(defaultSnapshotCacheSize),
 		log:                   log,
 		vpLock:                &sync.RWMutex{},
 	}
 }
 
-// Init sets voting power and quorum size
+// Init sets voting power and quorum size for height, reusing a cached
+// snapshot when the backend reports the validator set hasn't changed
 func (vm *ValidatorManager) Init(height uint64) error {
+	setHash, err := vm.currentSetHash(height)
+	if err != nil {
+		return err
+	}
+
+	if cached, ok := vm.snapshots.get(height); ok && bytes.Equal(cached.setHash, setHash) {
+		vm.setCurrentSnapshot(cached)
+
+		return nil
+	}
+
 	validatorsVotingPower, err := vm.backend.GetVotingPowers(height)
 	if err != nil {
 		return err
 	}
 
-	return vm.setCurrentVotingPower(validatorsVotingPower)
+	snapshot, err := vm.buildSnapshot(validatorsVotingPower, setHash)
+	if err != nil {
+		return err
+	}
+
+	vm.setCurrentSnapshot(snapshot)
+	vm.snapshots.put(height, snapshot)
+
+	return nil
 }
 
-// setCurrentVotingPower sets the current total voting power and quorum size
-// based on current validators voting power
-func (vm *ValidatorManager) setCurrentVotingPower(validatorsVotingPower map[string]*big.Int) error {
-	vm.vpLock.Lock()
-	defer vm.vpLock.Unlock()
+// currentSetHash returns the validator set hash for height if the
+// backend implements ValidatorSetHasher, or nil otherwise
+func (vm *ValidatorManager) currentSetHash(height uint64) ([]byte, error) {
+	hasher, ok := vm.backend.(ValidatorSetHasher)
+	if !ok {
+		return nil, nil
+	}
+
+	return hasher.ValidatorSetHash(height)
+}
 
+// buildSnapshot computes the total voting power and quorum size for
+// validatorsVotingPower, validating BFT safety when the policy requires it
+func (vm *ValidatorManager) buildSnapshot(
+	validatorsVotingPower map[string]*big.Int,
+	setHash []byte,
+) (*validatorSnapshot, error) {
 	totalVotingPower := calculateTotalVotingPower(validatorsVotingPower)
 	if totalVotingPower.Cmp(big.NewInt(0)) <= 0 {
-		return errVotingPowerNotCorrect
+		return nil, errVotingPowerNotCorrect
+	}
+
+	quorumSize := vm.policy.QuorumSize(totalVotingPower)
+	if vm.policy.RequiresBFT() {
+		if err := validateBFTSafety(quorumSize, totalVotingPower); err != nil {
+			return nil, err
+		}
 	}
 
-	vm.validatorsVotingPower = validatorsVotingPower
-	vm.quorumSize = calculateQuorum(totalVotingPower)
+	return &validatorSnapshot{
+		setHash:               setHash,
+		totalVotingPower:      totalVotingPower,
+		quorumSize:            quorumSize,
+		validatorsVotingPower: validatorsVotingPower,
+	}, nil
+}
 
-	return nil
+// setCurrentSnapshot makes snapshot the current voting power and quorum
+// size used by HasQuorum and HasPrepareQuorum
+func (vm *ValidatorManager) setCurrentSnapshot(snapshot *validatorSnapshot) {
+	vm.vpLock.Lock()
+	defer vm.vpLock.Unlock()
+
+	vm.validatorsVotingPower = snapshot.validatorsVotingPower
+	vm.totalVotingPower = snapshot.totalVotingPower
+	vm.quorumSize = snapshot.quorumSize
 }
 
-// HasQuorum provides information on whether messages have reached the quorum
-func (vm *ValidatorManager) HasQuorum(sendersAddrs map[string]struct{}) bool {
+// GetVotingPower returns the voting power recorded for addr at height,
+// using the cached snapshot when available
+func (vm *ValidatorManager) GetVotingPower(height uint64, addr string) (*big.Int, error) {
+	snapshot, err := vm.snapshotAt(height)
+	if err != nil {
+		return nil, err
+	}
+
+	power, ok := snapshot.validatorsVotingPower[addr]
+	if !ok {
+		return big.NewInt(0), nil
+	}
+
+	return power, nil
+}
+
+// SnapshotAt returns the total and per-validator voting power recorded
+// for height, fetching and caching it from the backend if necessary
+func (vm *ValidatorManager) SnapshotAt(height uint64) (*big.Int, map[string]*big.Int, error) {
+	snapshot, err := vm.snapshotAt(height)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return snapshot.totalVotingPower, snapshot.validatorsVotingPower, nil
+}
+
+// snapshotAt returns the cached snapshot for height, or fetches one from
+// the backend and caches it
+func (vm *ValidatorManager) snapshotAt(height uint64) (*validatorSnapshot, error) {
+	if cached, ok := vm.snapshots.get(height); ok {
+		return cached, nil
+	}
+
+	validatorsVotingPower, err := vm.backend.GetVotingPowers(height)
+	if err != nil {
+		return nil, err
+	}
+
+	setHash, err := vm.currentSetHash(height)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot, err := vm.buildSnapshot(validatorsVotingPower, setHash)
+	if err != nil {
+		return nil, err
+	}
+
+	vm.snapshots.put(height, snapshot)
+
+	return snapshot, nil
+}
+
+// MaximumFaultyVotingPower returns the largest voting power that can be
+// withheld or malicious while the validator set still reaches quorum
+func (vm *ValidatorManager) MaximumFaultyVotingPower() *big.Int {
 	vm.vpLock.RLock()
 	defer vm.vpLock.RUnlock()
 
+	return new(big.Int).Sub(vm.totalVotingPower, vm.quorumSize)
+}
+
+// HasQuorum provides information on whether messages have reached the
+// quorum. It is kept as a thin wrapper over Tally for backward
+// compatibility: set-membership maps to all-Yes votes, and the result is
+// checked against the quorum policy's quorumSize rather than TallyResult.Passed
+func (vm *ValidatorManager) HasQuorum(sendersAddrs map[string]struct{}) bool {
+	vm.vpLock.RLock()
+	initialized := vm.validatorsVotingPower != nil
+	vm.vpLock.RUnlock()
+
 	// if not initialized correctly return false
-	if vm.validatorsVotingPower == nil {
+	if !initialized {
 		return false
 	}
 
-	messageVotePower := big.NewInt(0)
-
+	votes := make(map[string]VoteOption, len(sendersAddrs))
 	for from := range sendersAddrs {
-		if vote, ok := vm.validatorsVotingPower[from]; ok {
-			messageVotePower.Add(messageVotePower, vote)
-		}
+		votes[from] = VoteYes
 	}
 
-	// aggVotingPower >= (2 * totalVotingPower / 3) + 1
-	return messageVotePower.Cmp(vm.quorumSize) >= 0
+	result := vm.Tally(votes)
+
+	vm.vpLock.RLock()
+	defer vm.vpLock.RUnlock()
+
+	// aggVotingPower >= quorumSize
+	return result.YesPower.Cmp(vm.quorumSize) >= 0
 }
 
 // HasPrepareQuorum provides information on whether prepared messages have reached the quorum
@@ -127,43 +298,6 @@ func (vm *ValidatorManager) HasPrepareQuorum(stateName stateType, proposalMessag
 	return vm.HasQuorum(sendersAddressesMap)
 }
 
-// calculateQuorum calculates quorum size which is FLOOR(2 * totalVotingPower / 3) + 1
-//func calculateQuorum(totalVotingPower *big.Int) *big.Int {
-//	quorum := new(big.Int).Mul(totalVotingPower, big.NewInt(2))
-
-// this will floor the (2 * totalVotingPower/3) and add 1
-//	return quorum.Div(quorum, big.NewInt(3)).Add(quorum, big.NewInt(1))
-//}
-
-// This below function accept 60%-40% mean 60% must be active , protocol can tollerate 40% of validators offlibe
-
-func calculateQuorum(totalVotingPower *big.Int) *big.Int {
-	// Multiply totalVotingPower by 6 and then divide by 10
-	// totalVotingPower := big.NewInt(4)
-	quorumPercentage := 0.6 // 60%
-
-	// Convert total voting power to big.Float
-	totalVotingPowerFloat := new(big.Float).SetInt(totalVotingPower)
-
-	// Convert quorum percentage to big.Float
-	quorumPercentageFloat := big.NewFloat(quorumPercentage)
-
-	// Perform the calculation: quorumPercentage * totalVotingPower
-	quorumFloat := new(big.Float).Mul(quorumPercentageFloat, totalVotingPowerFloat)
-
-	// Convert the result to a float64
-	quorumFloat64, _ := quorumFloat.Float64()
-
-	// Apply math.Ceil to the result
-	quorumCeilFloat64 := (math.Ceil(quorumFloat64))
-	quorumCeilBigFloat := new(big.Float).SetFloat64(quorumCeilFloat64)
-	quorumBigInt := new(big.Int)
-	quorumCeilBigFloat.Int(quorumBigInt)
-
-	// If no remainder, return the quorum as it is
-	return quorumBigInt
-}
-
 func calculateTotalVotingPower(validatorsVotingPower map[string]*big.Int) *big.Int {
 	totalVotingPower := big.NewInt(0)
 	for _, validatorVotingPower := range validatorsVotingPower {
@@ -0,0 +1,72 @@
+package core
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/tarality/0xTaral/messages/proto"
+)
+
+var errNotVoteMessage = errors.New("message is not a MessageType_VOTE message")
+
+// VoteDecoder extracts the VoteOption a MessageType_VOTE message carries.
+// The concrete decoding of the message's VOTE payload lives wherever
+// proto.Message's payload handling is defined, which sits outside this
+// source tree; VoteRound only needs the decoded result.
+type VoteDecoder func(message *proto.Message) (VoteOption, error)
+
+// VoteRound accumulates MessageType_VOTE messages into a running Tally for
+// a single governance round, so a consensus engine can drive
+// quorum/threshold/veto decisions with the same Tally machinery HasQuorum
+// is built on, instead of a separate quorum implementation.
+type VoteRound struct {
+	mux    sync.RWMutex
+	vm     *ValidatorManager
+	decode VoteDecoder
+	votes  map[string]VoteOption
+}
+
+// NewVoteRound creates a VoteRound that tallies against vm's current
+// validator set, decoding each incoming message's vote with decode
+func NewVoteRound(vm *ValidatorManager, decode VoteDecoder) *VoteRound {
+	return &VoteRound{
+		vm:     vm,
+		decode: decode,
+		votes:  make(map[string]VoteOption),
+	}
+}
+
+// AddMessage records the vote carried by message, keyed by its sender.
+// A later message from the same sender overwrites its earlier vote,
+// mirroring how a validator may change its vote before the round closes.
+// Safe to call concurrently with Tally, e.g. from a message-handling
+// goroutine while another goroutine polls for quorum.
+func (r *VoteRound) AddMessage(message *proto.Message) error {
+	if message.Type != proto.MessageType_VOTE {
+		return errNotVoteMessage
+	}
+
+	option, err := r.decode(message)
+	if err != nil {
+		return err
+	}
+
+	r.mux.Lock()
+	r.votes[string(message.From)] = option
+	r.mux.Unlock()
+
+	return nil
+}
+
+// Tally returns the TallyResult for the votes recorded so far
+func (r *VoteRound) Tally() TallyResult {
+	r.mux.RLock()
+	votes := make(map[string]VoteOption, len(r.votes))
+
+	for addr, option := range r.votes {
+		votes[addr] = option
+	}
+	r.mux.RUnlock()
+
+	return r.vm.Tally(votes)
+}
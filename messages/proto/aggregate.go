@@ -0,0 +1,15 @@
+package proto
+
+// AggregatedQuorumCertificate is a Commit quorum certificate carrying a
+// single aggregated BLS signature instead of one signature per signer.
+type AggregatedQuorumCertificate struct {
+	// Signers is a bitmap over the sorted validator set; bit i is set
+	// if that validator's signature is included in AggregatedSignature
+	Signers []byte
+
+	// AggregatedSignature is the BLS aggregate of every signer's Commit signature
+	AggregatedSignature []byte
+
+	// MessageHash is the hash the aggregated signature was produced over
+	MessageHash []byte
+}
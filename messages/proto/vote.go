@@ -0,0 +1,22 @@
+package proto
+
+// MessageType_VOTE carries a governance VoteOption for a weighted tally
+// round, reusing the IBFT message transport to drive on-chain
+// parameter/governance decisions with the same infrastructure as consensus.
+//
+// It follows the same numbering scheme as the other MessageType_*
+// constants (PREPREPARE, PREPARE, COMMIT, ROUND_CHANGE), whose protobuf
+// enum registration (the MessageType_name/_value maps backing
+// MessageType.String(), and the wire encoding in Message.Marshal) lives
+// outside this source tree. Regenerating those alongside this constant
+// is a follow-up once the full .proto definitions are available here.
+const MessageType_VOTE MessageType = 4
+
+// VotePayload is the MessageType_VOTE payload: a single validator's raw
+// vote choice for the round identified by the enclosing Message's View.
+// Option mirrors core.VoteOption (VoteYes=0, VoteNo=1, VoteAbstain=2,
+// VoteNoWithVeto=3); kept as a plain int32 here so this package, which
+// core imports, doesn't import core back.
+type VotePayload struct {
+	Option int32
+}
@@ -0,0 +1,84 @@
+package p2p
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"sync"
+
+	"github.com/tarality/0xTaral/messages/proto"
+)
+
+// defaultDedupCacheSize bounds how many recent (view, type, from, hash)
+// keys are remembered before the oldest is evicted
+const defaultDedupCacheSize = 4096
+
+// dedupCache is a fixed-size set of recently seen message keys, used to
+// drop duplicate gossip before it reaches the MessageQueue
+type dedupCache struct {
+	mux   sync.Mutex
+	size  int
+	seen  map[[32]byte]*list.Element
+	order *list.List
+}
+
+// newDedupCache creates a dedupCache holding up to size keys. A
+// non-positive size falls back to defaultDedupCacheSize
+func newDedupCache(size int) *dedupCache {
+	if size <= 0 {
+		size = defaultDedupCacheSize
+	}
+
+	return &dedupCache{
+		size:  size,
+		seen:  make(map[[32]byte]*list.Element),
+		order: list.New(),
+	}
+}
+
+// addIfNew records key and reports whether it hadn't been seen before
+func (c *dedupCache) addIfNew(key [32]byte) bool {
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	if element, ok := c.seen[key]; ok {
+		c.order.MoveToFront(element)
+
+		return false
+	}
+
+	element := c.order.PushFront(key)
+	c.seen[key] = element
+
+	if c.order.Len() > c.size {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.seen, oldest.Value.([32]byte))
+	}
+
+	return true
+}
+
+// dedupKey derives a (view, type, from, hash) identity for a raw gossip
+// message, so the same message arriving from multiple peers is only
+// routed to the MessageQueue once
+func dedupKey(message *proto.Message, raw []byte) [32]byte {
+	hasher := sha256.New()
+
+	var viewBuf [16]byte
+	binary.BigEndian.PutUint64(viewBuf[0:8], message.View.Height)
+	binary.BigEndian.PutUint64(viewBuf[8:16], message.View.Round)
+	hasher.Write(viewBuf[:])
+
+	var typeBuf [4]byte
+	binary.BigEndian.PutUint32(typeBuf[:], uint32(message.Type))
+	hasher.Write(typeBuf[:])
+
+	hasher.Write(message.From)
+	hasher.Write(raw)
+
+	var key [32]byte
+	copy(key[:], hasher.Sum(nil))
+
+	return key
+}
@@ -0,0 +1,83 @@
+package p2p
+
+import (
+	"sync"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+
+	"github.com/tarality/0xTaral/messages/proto"
+)
+
+// PeerState is the last round/step a remote peer is known to be in,
+// learned by observing the messages it gossips
+type PeerState struct {
+	Height uint64
+	Round  uint64
+	Step   proto.MessageType
+}
+
+// isBehind reports whether a message for (height, round) is something
+// this peer state has not yet reported seeing
+func (s PeerState) isBehind(height, round uint64) bool {
+	if height != s.Height {
+		return height > s.Height
+	}
+
+	return round > s.Round
+}
+
+// PeerSet is a concurrency-safe registry of PeerState keyed by peer ID,
+// separate from the gossip routing layer so routing decisions (what to
+// send) stay independent of what has been learned about each peer
+type PeerSet struct {
+	mux   sync.RWMutex
+	peers map[peer.ID]PeerState
+}
+
+// NewPeerSet creates an empty PeerSet
+func NewPeerSet() *PeerSet {
+	return &PeerSet{
+		peers: make(map[peer.ID]PeerState),
+	}
+}
+
+// Update records the view and step that message implies for id
+func (s *PeerSet) Update(id peer.ID, message *proto.Message) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.peers[id] = PeerState{
+		Height: message.View.Height,
+		Round:  message.View.Round,
+		Step:   message.Type,
+	}
+}
+
+// Get returns the last known state for id
+func (s *PeerSet) Get(id peer.ID) (PeerState, bool) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	state, ok := s.peers[id]
+
+	return state, ok
+}
+
+// Missing filters have down to the messages id has not reported seeing yet,
+// so catch-up only sends what a peer is actually missing
+func (s *PeerSet) Missing(id peer.ID, have []*proto.Message) []*proto.Message {
+	state, ok := s.Get(id)
+	if !ok {
+		return have
+	}
+
+	missing := make([]*proto.Message, 0, len(have))
+
+	for _, message := range have {
+		if state.isBehind(message.View.Height, message.View.Round) {
+			missing = append(missing, message)
+		}
+	}
+
+	return missing
+}
@@ -0,0 +1,281 @@
+// Package p2p provides a libp2p-backed implementation of core.Transport:
+// pubsub gossip per IBFT message type, inbound deduplication, peer state
+// tracking and catch-up delivery for messages a peer hasn't seen.
+package p2p
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"sync"
+
+	pubsub "github.com/libp2p/go-libp2p-pubsub"
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/libp2p/go-libp2p/core/protocol"
+
+	"github.com/tarality/0xTaral/core"
+	"github.com/tarality/0xTaral/messages/proto"
+)
+
+// topicPrefix namespaces gossip topics so multiple chains/forks sharing
+// the same libp2p host don't cross-talk
+const topicPrefix = "/0xtaral/consensus/"
+
+// catchUpProtocolID is a dedicated libp2p stream protocol for catch-up
+// delivery, so a missing message is unicast directly to the peer that
+// needs it instead of re-gossiped to the whole topic
+const catchUpProtocolID protocol.ID = "/0xtaral/catchup/1.0.0"
+
+// maxCatchUpMessageSize bounds a single catch-up frame read off the wire
+const maxCatchUpMessageSize = 4 << 20
+
+// gossipedMessageTypes are the IBFT message types each given its own
+// pubsub topic, mirroring the Preprepare/Prepare/Commit/RoundChange split
+var gossipedMessageTypes = []proto.MessageType{
+	proto.MessageType_PREPREPARE,
+	proto.MessageType_PREPARE,
+	proto.MessageType_COMMIT,
+	proto.MessageType_ROUND_CHANGE,
+}
+
+// MessageQueue is the minimal surface the transport needs to hand off
+// validated, deduplicated messages to the consensus engine
+type MessageQueue interface {
+	AddMessage(message *proto.Message)
+}
+
+// Transport is a libp2p pubsub-based implementation of core.Transport
+type Transport struct {
+	host   host.Host
+	pubsub *pubsub.PubSub
+
+	topics map[proto.MessageType]*pubsub.Topic
+	subs   map[proto.MessageType]*pubsub.Subscription
+
+	// peers tracks what round/step each remote peer last reported,
+	// kept separate from the routing loop below (mirrors the
+	// PeerState/routing split used by Tendermint's consensus reactor)
+	peers *PeerSet
+	seen  *dedupCache
+	queue MessageQueue
+
+	log core.Logger
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewTransport creates a Transport bound to h, joins the gossip topic for
+// every message type in gossipedMessageTypes, and starts routing inbound
+// messages into queue
+func NewTransport(
+	ctx context.Context,
+	h host.Host,
+	ps *pubsub.PubSub,
+	queue MessageQueue,
+	log core.Logger,
+) (*Transport, error) {
+	topics := make(map[proto.MessageType]*pubsub.Topic, len(gossipedMessageTypes))
+	subs := make(map[proto.MessageType]*pubsub.Subscription, len(gossipedMessageTypes))
+
+	for _, msgType := range gossipedMessageTypes {
+		topic, err := ps.Join(topicPrefix + msgType.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to join topic for %s: %w", msgType, err)
+		}
+
+		sub, err := topic.Subscribe()
+		if err != nil {
+			return nil, fmt.Errorf("failed to subscribe to topic for %s: %w", msgType, err)
+		}
+
+		topics[msgType] = topic
+		subs[msgType] = sub
+	}
+
+	transportCtx, cancel := context.WithCancel(ctx)
+
+	t := &Transport{
+		host:   h,
+		pubsub: ps,
+		topics: topics,
+		subs:   subs,
+		peers:  NewPeerSet(),
+		seen:   newDedupCache(defaultDedupCacheSize),
+		queue:  queue,
+		log:    log,
+		ctx:    transportCtx,
+		cancel: cancel,
+	}
+
+	for msgType, sub := range subs {
+		t.wg.Add(1)
+
+		go t.readLoop(msgType, sub)
+	}
+
+	h.SetStreamHandler(catchUpProtocolID, t.handleCatchUpStream)
+
+	return t, nil
+}
+
+// Multicast publishes message on the gossip topic for its message type,
+// satisfying core.Transport
+func (t *Transport) Multicast(message *proto.Message) {
+	topic, ok := t.topics[message.Type]
+	if !ok {
+		t.log.Error(fmt.Sprintf("p2p transport - no topic joined for message type %s", message.Type))
+
+		return
+	}
+
+	raw, err := message.Marshal()
+	if err != nil {
+		t.log.Error(fmt.Sprintf("p2p transport - failed to marshal message: %v", err))
+
+		return
+	}
+
+	if err := topic.Publish(t.ctx, raw); err != nil {
+		t.log.Error(fmt.Sprintf("p2p transport - failed to publish message: %v", err))
+	}
+}
+
+// SendCatchUp unicasts, over a direct stream to id, only the messages in
+// have that id has not yet reported seeing, instead of re-gossiping
+// everything to every peer on the pubsub topic
+func (t *Transport) SendCatchUp(id peer.ID, have []*proto.Message) {
+	for _, message := range t.peers.Missing(id, have) {
+		t.sendDirect(id, message)
+	}
+}
+
+// sendDirect opens a stream to id over catchUpProtocolID and writes a
+// single length-prefixed message, closing the stream once written
+func (t *Transport) sendDirect(id peer.ID, message *proto.Message) {
+	raw, err := message.Marshal()
+	if err != nil {
+		t.log.Error(fmt.Sprintf("p2p transport - failed to marshal catch-up message: %v", err))
+
+		return
+	}
+
+	stream, err := t.host.NewStream(t.ctx, id, catchUpProtocolID)
+	if err != nil {
+		t.log.Error(fmt.Sprintf("p2p transport - failed to open catch-up stream to %s: %v", id, err))
+
+		return
+	}
+	defer stream.Close()
+
+	var lengthBuf [4]byte
+	binary.BigEndian.PutUint32(lengthBuf[:], uint32(len(raw)))
+
+	if _, err := stream.Write(lengthBuf[:]); err != nil {
+		t.log.Error(fmt.Sprintf("p2p transport - failed to write catch-up length to %s: %v", id, err))
+
+		return
+	}
+
+	if _, err := stream.Write(raw); err != nil {
+		t.log.Error(fmt.Sprintf("p2p transport - failed to write catch-up message to %s: %v", id, err))
+	}
+}
+
+// handleCatchUpStream reads the single length-prefixed message a peer
+// unicasts over catchUpProtocolID and, after deduplication, routes it
+// into the MessageQueue just like a gossiped message
+func (t *Transport) handleCatchUpStream(stream network.Stream) {
+	defer stream.Close()
+
+	var lengthBuf [4]byte
+	if _, err := io.ReadFull(stream, lengthBuf[:]); err != nil {
+		t.log.Error(fmt.Sprintf("p2p transport - failed to read catch-up length from %s: %v", stream.Conn().RemotePeer(), err))
+
+		return
+	}
+
+	length := binary.BigEndian.Uint32(lengthBuf[:])
+	if length > maxCatchUpMessageSize {
+		t.log.Error(fmt.Sprintf("p2p transport - catch-up message from %s exceeds maximum size", stream.Conn().RemotePeer()))
+
+		return
+	}
+
+	raw := make([]byte, length)
+	if _, err := io.ReadFull(stream, raw); err != nil {
+		t.log.Error(fmt.Sprintf("p2p transport - failed to read catch-up message from %s: %v", stream.Conn().RemotePeer(), err))
+
+		return
+	}
+
+	message := &proto.Message{}
+	if err := message.Unmarshal(raw); err != nil {
+		t.log.Error(fmt.Sprintf("p2p transport - failed to unmarshal catch-up message from %s: %v", stream.Conn().RemotePeer(), err))
+
+		return
+	}
+
+	if !t.seen.addIfNew(dedupKey(message, raw)) {
+		return
+	}
+
+	t.peers.Update(stream.Conn().RemotePeer(), message)
+	t.queue.AddMessage(message)
+}
+
+// Close cancels all subscriptions and waits for the read loops to exit
+func (t *Transport) Close() {
+	t.cancel()
+	t.wg.Wait()
+
+	for _, sub := range t.subs {
+		sub.Cancel()
+	}
+}
+
+// readLoop feeds messages received on sub into the MessageQueue, after
+// deduplication and peer state tracking
+func (t *Transport) readLoop(msgType proto.MessageType, sub *pubsub.Subscription) {
+	defer t.wg.Done()
+
+	for {
+		raw, err := sub.Next(t.ctx)
+		if err != nil {
+			// context cancelled or subscription closed
+			return
+		}
+
+		if raw.ReceivedFrom == t.host.ID() {
+			continue
+		}
+
+		t.handleRaw(msgType, raw)
+	}
+}
+
+// handleRaw decodes, deduplicates and routes a single gossip message
+func (t *Transport) handleRaw(msgType proto.MessageType, raw *pubsub.Message) {
+	message := &proto.Message{}
+	if err := message.Unmarshal(raw.Data); err != nil {
+		t.log.Error(fmt.Sprintf("p2p transport - failed to unmarshal message for type %s: %v", msgType, err))
+
+		return
+	}
+
+	if !t.seen.addIfNew(dedupKey(message, raw.Data)) {
+		return
+	}
+
+	// raw.ReceivedFrom is the peer that forwarded this message to us over
+	// the gossipsub mesh, not necessarily the one who originated it.
+	// raw.GetFrom() decodes the signed pubsub envelope's From field
+	// instead, so PeerState reflects the actual publisher's progress
+	// under multi-hop gossip.
+	t.peers.Update(raw.GetFrom(), message)
+	t.queue.AddMessage(message)
+}